@@ -10,16 +10,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"github.com/gomodule/redigo/redis"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 const (
@@ -34,30 +39,220 @@ const (
 	testInterval = 10 * time.Second
 )
 
-var redisClient *redis.Client
+var redisClient *goredis.Client
+
+// Sentinel support is optional: set SENTINEL_ADDRS (comma-separated host:port
+// list) and SENTINEL_MASTER to exercise the failover-discovery dial path in
+// addition to the hard-coded redisAddr target. Many production macOS setups
+// hit this bug via Sentinel-resolved addresses rather than a static IP.
+var (
+	sentinelAddrs  []string
+	sentinelMaster string
+	sentinelClient *goredis.SentinelClient
+	failoverClient *goredis.Client
+)
+
+// outputFormat selects the per-cycle reporting style: "text" (default,
+// human-readable) or "json" (one structured record per cycle, for fleet-scale
+// collection and aggregation across many hosts/runs).
+var outputFormat string
+
+// testResult is one {name, target, ok, latency_ms, error, error_category}
+// entry in a cycleRecord's Tests array.
+type testResult struct {
+	Name          string  `json:"name"`
+	Target        string  `json:"target"`
+	OK            bool    `json:"ok"`
+	LatencyMS     float64 `json:"latency_ms"`
+	Error         string  `json:"error,omitempty"`
+	ErrorCategory string  `json:"error_category,omitempty"`
+}
+
+// cycleRecord is the structured, machine-readable form of one runTest() pass.
+type cycleRecord struct {
+	Timestamp    string       `json:"timestamp"`
+	PID          int          `json:"pid"`
+	PPID         int          `json:"ppid"`
+	Sess         string       `json:"sess"`
+	TTY          string       `json:"tty"`
+	Tests        []testResult `json:"tests"`
+	RouteOutput  string       `json:"route_output"`
+	ArpOutput    string       `json:"arp_output"`
+	PingOutput   string       `json:"ping_output"`
+	NetcatOutput string       `json:"netcat_output"`
+	NetRoute     string       `json:"net_route"`
+	NetArp       string       `json:"net_arp"`
+	NetLsof      string       `json:"net_lsof"`
+	NetSockName  string       `json:"net_sockname"`
+	NetStateDiff string       `json:"net_state_diff,omitempty"`
+}
+
+// classifyError buckets an error into a small set of categories so
+// downstream tooling can aggregate across many hosts/runs without regexing
+// free-form log text.
+func classifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "no route to host"):
+		return "no route to host"
+	case strings.Contains(msg, "connection refused"):
+		return "connection refused"
+	case strings.Contains(msg, "i/o timeout"):
+		return "i/o timeout"
+	case strings.Contains(msg, "context deadline exceeded"):
+		return "context deadline exceeded"
+	case strings.Contains(msg, "no such host") || strings.Contains(msg, "lookup "):
+		return "dns failure"
+	default:
+		return "unknown"
+	}
+}
+
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
+
+// lastTestOK tracks each test's previous-cycle outcome so we can detect a
+// ✅ -> ❌ transition. lastNetSnapshot holds the net-state snapshot from the
+// previous cycle so a transition can be diffed against it.
+var (
+	lastTestOK      = map[string]bool{}
+	lastNetSnapshot *netStateSnapshot
+)
+
+// netStateSnapshot is a per-cycle capture of this process's view of the
+// network: the routing table, ARP cache, open network file descriptors, and
+// the local/peer address of a freshly created (unconnected) socket. Diffing
+// this between a succeeding and a failing cycle shows whether the process's
+// routing view actually changes, or only the dial-path decision does.
+type netStateSnapshot struct {
+	Route    string
+	Arp      string
+	Lsof     string
+	SockName string
+}
+
+func captureNetState() netStateSnapshot {
+	snapshot := netStateSnapshot{
+		Route: runCaptureOutput("netstat", "-rn", "-f", "inet"),
+		Arp:   runCaptureOutput("arp", "-an"),
+		Lsof:  runCaptureOutput("lsof", "-p", strconv.Itoa(os.Getpid()), "-nP", "-i"),
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		snapshot.SockName = fmt.Sprintf("socket() failed: %v", err)
+		return snapshot
+	}
+	defer syscall.Close(fd)
+
+	local, err := syscall.Getsockname(fd)
+	if err != nil {
+		snapshot.SockName = fmt.Sprintf("getsockname() failed: %v", err)
+		return snapshot
+	}
+	_, peerErr := syscall.Getpeername(fd)
+	snapshot.SockName = fmt.Sprintf("local=%v peer=%v", local, peerErr)
+	return snapshot
+}
+
+func runCaptureOutput(name string, args ...string) string {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("ERROR running %s %v: %v\n%s", name, args, err, string(out))
+	}
+	return string(out)
+}
+
+// diffLines prints a minimal unified-diff-style view of which lines
+// disappeared or appeared between two snapshots of the same label.
+func diffLines(label, oldText, newText string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s (previous)\n+++ %s (current)\n", label, label)
+
+	newLines := strings.Split(newText, "\n")
+	newSet := make(map[string]bool, len(newLines))
+	for _, l := range newLines {
+		newSet[l] = true
+	}
+
+	oldLines := strings.Split(oldText, "\n")
+	oldSet := make(map[string]bool, len(oldLines))
+	for _, l := range oldLines {
+		oldSet[l] = true
+	}
+
+	for _, l := range oldLines {
+		if !newSet[l] {
+			fmt.Fprintf(&b, "-%s\n", l)
+		}
+	}
+	for _, l := range newLines {
+		if !oldSet[l] {
+			fmt.Fprintf(&b, "+%s\n", l)
+		}
+	}
+
+	return b.String()
+}
 
 func main() {
-	fmt.Println("===========================================")
-	fmt.Println("Go macOS Network Reproduction Test")
-	fmt.Println("===========================================")
-	fmt.Printf("PID: %d\n", os.Getpid())
-	fmt.Printf("PPID: %d\n", os.Getppid())
-	fmt.Printf("OS: %s\n", runtime.GOOS)
-	fmt.Printf("Arch: %s\n", runtime.GOARCH)
-	fmt.Printf("Target Redis: %s\n", redisAddr)
-	fmt.Println("===========================================")
-	fmt.Println()
-	fmt.Println("This test will run every 10 seconds.")
-	fmt.Println("After starting via SSH, disconnect the SSH session.")
-	fmt.Println("Watch the logs to see Go networking fail while system tools succeed.")
-	fmt.Println()
+	flag.StringVar(&outputFormat, "format", "text", "per-cycle output format: text|json")
+	flag.Parse()
+
+	if outputFormat != "text" && outputFormat != "json" {
+		fmt.Fprintf(os.Stderr, "invalid -format %q: must be text or json\n", outputFormat)
+		os.Exit(1)
+	}
+
+	if outputFormat == "text" {
+		fmt.Println("===========================================")
+		fmt.Println("Go macOS Network Reproduction Test")
+		fmt.Println("===========================================")
+		fmt.Printf("PID: %d\n", os.Getpid())
+		fmt.Printf("PPID: %d\n", os.Getppid())
+		fmt.Printf("OS: %s\n", runtime.GOOS)
+		fmt.Printf("Arch: %s\n", runtime.GOARCH)
+		fmt.Printf("Target Redis: %s\n", redisAddr)
+		fmt.Println("===========================================")
+		fmt.Println()
+		fmt.Println("This test will run every 10 seconds.")
+		fmt.Println("After starting via SSH, disconnect the SSH session.")
+		fmt.Println("Watch the logs to see Go networking fail while system tools succeed.")
+		fmt.Println()
+	}
 
 	// Initialize Redis client
-	redisClient = redis.NewClient(&redis.Options{
+	redisClient = goredis.NewClient(&goredis.Options{
 		Addr:        redisAddr,
 		DialTimeout: 5 * time.Second,
 	})
 
+	// Initialize Sentinel clients, if configured
+	if addrs := os.Getenv("SENTINEL_ADDRS"); addrs != "" {
+		sentinelAddrs = strings.Split(addrs, ",")
+		sentinelMaster = os.Getenv("SENTINEL_MASTER")
+		if outputFormat == "text" {
+			fmt.Printf("Sentinel addrs: %v\n", sentinelAddrs)
+			fmt.Printf("Sentinel master: %s\n", sentinelMaster)
+		}
+
+		sentinelClient = goredis.NewSentinelClient(&goredis.Options{
+			Addr:        sentinelAddrs[0],
+			DialTimeout: 5 * time.Second,
+		})
+
+		failoverClient = goredis.NewFailoverClient(&goredis.FailoverOptions{
+			MasterName:    sentinelMaster,
+			SentinelAddrs: sentinelAddrs,
+			DialTimeout:   5 * time.Second,
+		})
+	}
+
 	// Run test immediately
 	runTest()
 
@@ -71,70 +266,187 @@ func main() {
 }
 
 func runTest() {
-	fmt.Println("-------------------------------------------")
-	fmt.Printf("Test run at: %s\n", time.Now().Format(time.RFC3339))
-	fmt.Println("-------------------------------------------")
+	isText := outputFormat == "text"
+
+	if isText {
+		fmt.Println("-------------------------------------------")
+		fmt.Printf("Test run at: %s\n", time.Now().Format(time.RFC3339))
+		fmt.Println("-------------------------------------------")
+	}
+
+	rec := cycleRecord{
+		Timestamp: time.Now().Format(time.RFC3339),
+		PID:       os.Getpid(),
+		PPID:      os.Getppid(),
+	}
+	rec.Sess, rec.TTY = printProcessInfo(isText)
+
+	if isText {
+		fmt.Println("\n[TEST 1] go-redis client PING to Redis...")
+	}
+	rec.Tests = append(rec.Tests, testGoRedisPing(isText))
+
+	if isText {
+		fmt.Println("\n[TEST 2] go-redis client SET/GET...")
+	}
+	rec.Tests = append(rec.Tests, testGoRedisSetGet(isText))
+
+	if isText {
+		fmt.Println("\n[TEST 3] Go net.DialTimeout to Redis (local network)...")
+	}
+	rec.Tests = append(rec.Tests, testGoDialRedis(isText))
+
+	if isText {
+		fmt.Println("\n[TEST 4] Go net.DialTimeout to Google (internet)...")
+	}
+	rec.Tests = append(rec.Tests, testGoDialGoogle(isText))
+
+	if isText {
+		fmt.Println("\n[TEST 5] Go net.DialTimeout to 8.8.8.8 (internet IP)...")
+	}
+	rec.Tests = append(rec.Tests, testGoDialGoogleDNS(isText))
 
-	// Get process info
-	printProcessInfo()
+	if isText {
+		fmt.Println("\n[TEST 6] System ping to Redis host...")
+	}
+	pingResult, pingOutput := testSystemPing(isText)
+	rec.Tests = append(rec.Tests, pingResult)
+	rec.PingOutput = pingOutput
+
+	if isText {
+		fmt.Println("\n[TEST 7] System nc (netcat) to Redis...")
+	}
+	netcatResult, netcatOutput := testSystemNetcat(isText)
+	rec.Tests = append(rec.Tests, netcatResult)
+	rec.NetcatOutput = netcatOutput
 
-	// Test 1: go-redis client PING - this will fail after SSH disconnect
-	fmt.Println("\n[TEST 1] go-redis client PING to Redis...")
-	testGoRedisPing()
+	if isText {
+		fmt.Println("\n[TEST 8] Go net.Dial with explicit en0 binding...")
+	}
+	rec.Tests = append(rec.Tests, testGoDialWithInterfaceBinding(isText))
 
-	// Test 2: go-redis client SET/GET - this will fail after SSH disconnect
-	fmt.Println("\n[TEST 2] go-redis client SET/GET...")
-	testGoRedisSetGet()
+	if isText {
+		fmt.Println("\n[TEST 9] Route to Redis host...")
+	}
+	routeResult, routeOutput := testRouteGet(isText)
+	rec.Tests = append(rec.Tests, routeResult)
+	rec.RouteOutput = routeOutput
 
-	// Test 3: Go net.Dial to Redis (local network) - this will fail after SSH disconnect
-	fmt.Println("\n[TEST 3] Go net.DialTimeout to Redis (local network)...")
-	testGoDialRedis()
+	if isText {
+		fmt.Println("\n[TEST 10] ARP entry for Redis host...")
+	}
+	arpResult, arpOutput := testArp(isText)
+	rec.Tests = append(rec.Tests, arpResult)
+	rec.ArpOutput = arpOutput
 
-	// Test 4: Go net.Dial to Google (internet) - this will succeed
-	fmt.Println("\n[TEST 4] Go net.DialTimeout to Google (internet)...")
-	testGoDialGoogle()
+	// Test 11: redigo client PING - isolates go-redis vs. any Go Redis client
+	if isText {
+		fmt.Println("\n[TEST 11] redigo client PING to Redis...")
+	}
+	rec.Tests = append(rec.Tests, testRedigoPing(isText))
 
-	// Test 5: Go net.Dial to 8.8.8.8 (internet IP) - this will succeed
-	fmt.Println("\n[TEST 5] Go net.DialTimeout to 8.8.8.8 (internet IP)...")
-	testGoDialGoogleDNS()
+	// Test 12: redigo client SET/GET - isolates go-redis vs. any Go Redis client
+	if isText {
+		fmt.Println("\n[TEST 12] redigo client SET/GET...")
+	}
+	rec.Tests = append(rec.Tests, testRedigoSetGet(isText))
 
-	// Test 6: System ping to Redis host - this will succeed!
-	fmt.Println("\n[TEST 6] System ping to Redis host...")
-	testSystemPing()
+	if sentinelClient != nil {
+		// Test 13: Ping the Sentinel itself
+		if isText {
+			fmt.Println("\n[TEST 13] go-redis PING to Sentinel...")
+		}
+		rec.Tests = append(rec.Tests, testSentinelPing(isText))
 
-	// Test 7: System nc (netcat) to Redis - this will succeed!
-	fmt.Println("\n[TEST 7] System nc (netcat) to Redis...")
-	testSystemNetcat()
+		// Test 14: Resolve the master via Sentinel
+		if isText {
+			fmt.Println("\n[TEST 14] Sentinel get-master-addr-by-name...")
+		}
+		rec.Tests = append(rec.Tests, testSentinelGetMasterAddr(isText))
 
-	// Test 8: Go with explicit interface binding - still fails!
-	fmt.Println("\n[TEST 8] Go net.Dial with explicit en0 binding...")
-	testGoDialWithInterfaceBinding()
+		// Test 15: Ping the Sentinel-resolved master through the failover client
+		if isText {
+			fmt.Println("\n[TEST 15] go-redis failover client PING (Sentinel-resolved master)...")
+		}
+		rec.Tests = append(rec.Tests, testFailoverPing(isText))
+	}
 
-	// Test 9: Check route
-	fmt.Println("\n[TEST 9] Route to Redis host...")
-	testRouteGet()
+	// Test 16: raw syscall socket dial, bypassing Go's net package entirely
+	if isText {
+		fmt.Println("\n[TEST 16] Raw syscall socket dial to Redis...")
+	}
+	rec.Tests = append(rec.Tests, testRawSyscallDial(isText))
+
+	// Snapshot routing/ARP/fd state every cycle, and print a diff against the
+	// previous cycle's snapshot the moment any test transitions from ✅ to ❌.
+	snapshot := captureNetState()
+	transitioned := false
+	for _, t := range rec.Tests {
+		if prevOK, seen := lastTestOK[t.Name]; seen && prevOK && !t.OK {
+			transitioned = true
+		}
+		lastTestOK[t.Name] = t.OK
+	}
+	rec.NetRoute = snapshot.Route
+	rec.NetArp = snapshot.Arp
+	rec.NetLsof = snapshot.Lsof
+	rec.NetSockName = snapshot.SockName
+
+	if transitioned && lastNetSnapshot != nil {
+		diff := diffLines("route", lastNetSnapshot.Route, snapshot.Route) +
+			diffLines("arp", lastNetSnapshot.Arp, snapshot.Arp) +
+			diffLines("lsof", lastNetSnapshot.Lsof, snapshot.Lsof) +
+			diffLines("sockname", lastNetSnapshot.SockName, snapshot.SockName)
+		rec.NetStateDiff = diff
+		if isText {
+			fmt.Println("\n[NET STATE] A test transitioned from ✅ to ❌ this cycle. Diff vs. previous snapshot:")
+			fmt.Print(diff)
+		}
+	}
+	lastNetSnapshot = &snapshot
 
-	// Test 10: Check ARP
-	fmt.Println("\n[TEST 10] ARP entry for Redis host...")
-	testArp()
+	if isText {
+		fmt.Println("\n-------------------------------------------")
+		fmt.Println("Test complete. Waiting for next run...")
+		fmt.Println("-------------------------------------------")
+		fmt.Println()
+		return
+	}
 
-	fmt.Println("\n-------------------------------------------")
-	fmt.Println("Test complete. Waiting for next run...")
-	fmt.Println("-------------------------------------------")
-	fmt.Println()
+	encoded, err := json.Marshal(rec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal cycle record: %v\n", err)
+		return
+	}
+	fmt.Println(string(encoded))
 }
 
-func printProcessInfo() {
+// printProcessInfo snapshots pid/ppid/pgid/sess/tty via ps. ps is pointed at
+// $$ of a short-lived sh child, so the pid/ppid/pgid shown are that child's,
+// while its sess and tty are inherited from this process. It always returns the sess and tty values (for JSON records) and only
+// prints the human-readable form in text mode.
+func printProcessInfo(printText bool) (sess, tty string) {
 	cmd := exec.Command("sh", "-c", "ps -o pid,ppid,pgid,sess,tty,comm -p $$")
 	out, _ := cmd.CombinedOutput()
-	fmt.Printf("Process info:\n%s\n", string(out))
+	if printText {
+		fmt.Printf("Process info:\n%s\n", string(out))
+	}
+
+	sessCmd := exec.Command("sh", "-c", "ps -o sess= -p $$")
+	sessOut, _ := sessCmd.CombinedOutput()
+	sess = strings.TrimSpace(string(sessOut))
 
 	ttyCmd := exec.Command("tty")
 	ttyOut, _ := ttyCmd.CombinedOutput()
-	fmt.Printf("TTY: %s", string(ttyOut))
+	tty = strings.TrimSpace(string(ttyOut))
+	if printText {
+		fmt.Printf("TTY: %s\n", tty)
+	}
+
+	return sess, tty
 }
 
-func testGoRedisPing() {
+func testGoRedisPing(printText bool) testResult {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -143,18 +455,24 @@ func testGoRedisPing() {
 	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
-	} else {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "go-redis PING", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED: %s (latency: %v)\n", result, latency)
 	}
+	return testResult{Name: "go-redis PING", Target: redisAddr, OK: true, LatencyMS: msSince(start)}
 }
 
-func testGoRedisSetGet() {
+func testGoRedisSetGet(printText bool) testResult {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	testKey := "go-macos-network-repro-test"
 	testValue := fmt.Sprintf("test-value-%d", time.Now().Unix())
+	cycleStart := time.Now()
 
 	// SET
 	start := time.Now()
@@ -162,10 +480,14 @@ func testGoRedisSetGet() {
 	setLatency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ SET FAILED: %v (latency: %v)\n", err, setLatency)
-		return
+		if printText {
+			fmt.Printf("  ❌ SET FAILED: %v (latency: %v)\n", err, setLatency)
+		}
+		return testResult{Name: "go-redis SET/GET", Target: redisAddr, LatencyMS: msSince(cycleStart), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SET SUCCEEDED (latency: %v)\n", setLatency)
 	}
-	fmt.Printf("  ✅ SET SUCCEEDED (latency: %v)\n", setLatency)
 
 	// GET
 	start = time.Now()
@@ -173,85 +495,123 @@ func testGoRedisSetGet() {
 	getLatency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ GET FAILED: %v (latency: %v)\n", err, getLatency)
-	} else {
+		if printText {
+			fmt.Printf("  ❌ GET FAILED: %v (latency: %v)\n", err, getLatency)
+		}
+		return testResult{Name: "go-redis SET/GET", Target: redisAddr, LatencyMS: msSince(cycleStart), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
 		fmt.Printf("  ✅ GET SUCCEEDED: %s (latency: %v)\n", result, getLatency)
 	}
+	return testResult{Name: "go-redis SET/GET", Target: redisAddr, OK: true, LatencyMS: msSince(cycleStart)}
 }
 
-func testGoDialRedis() {
+func testGoDialRedis(printText bool) testResult {
 	start := time.Now()
 	conn, err := net.DialTimeout("tcp", redisAddr, 5*time.Second)
 	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
-	} else {
-		conn.Close()
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "net.DialTimeout redis", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	conn.Close()
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED (latency: %v)\n", latency)
 	}
+	return testResult{Name: "net.DialTimeout redis", Target: redisAddr, OK: true, LatencyMS: msSince(start)}
 }
 
-func testGoDialGoogle() {
+func testGoDialGoogle(printText bool) testResult {
 	start := time.Now()
 	conn, err := net.DialTimeout("tcp", googleAddr, 5*time.Second)
 	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
-	} else {
-		conn.Close()
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "net.DialTimeout google", Target: googleAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	conn.Close()
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED (latency: %v)\n", latency)
 	}
+	return testResult{Name: "net.DialTimeout google", Target: googleAddr, OK: true, LatencyMS: msSince(start)}
 }
 
-func testGoDialGoogleDNS() {
+func testGoDialGoogleDNS(printText bool) testResult {
 	start := time.Now()
 	conn, err := net.DialTimeout("tcp", googleDNSAddr, 5*time.Second)
 	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
-	} else {
-		conn.Close()
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "net.DialTimeout google-dns", Target: googleDNSAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	conn.Close()
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED (latency: %v)\n", latency)
 	}
+	return testResult{Name: "net.DialTimeout google-dns", Target: googleDNSAddr, OK: true, LatencyMS: msSince(start)}
 }
 
-func testSystemPing() {
+func testSystemPing(printText bool) (testResult, string) {
+	start := time.Now()
 	cmd := exec.Command("ping", "-c", "1", "-t", "2", redisHost)
 	out, err := cmd.CombinedOutput()
+	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
-	} else {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
+		}
+		return testResult{Name: "system ping", Target: redisHost, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}, string(out)
+	}
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED\n")
 	}
+	return testResult{Name: "system ping", Target: redisHost, OK: true, LatencyMS: float64(latency) / float64(time.Millisecond)}, string(out)
 }
 
-func testSystemNetcat() {
+func testSystemNetcat(printText bool) (testResult, string) {
+	start := time.Now()
 	cmd := exec.Command("nc", "-z", "-w", "2", redisHost, fmt.Sprintf("%d", redisPort))
 	out, err := cmd.CombinedOutput()
+	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
-	} else {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
+		}
+		return testResult{Name: "system nc", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}, string(out)
+	}
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED\n")
 	}
+	return testResult{Name: "system nc", Target: redisAddr, OK: true, LatencyMS: float64(latency) / float64(time.Millisecond)}, string(out)
 }
 
-func testGoDialWithInterfaceBinding() {
+func testGoDialWithInterfaceBinding(printText bool) testResult {
 	// Get en0's IP address
 	iface, err := net.InterfaceByName("en0")
 	if err != nil {
-		fmt.Printf("  ❌ Failed to get en0 interface: %v\n", err)
-		return
+		if printText {
+			fmt.Printf("  ❌ Failed to get en0 interface: %v\n", err)
+		}
+		return testResult{Name: "net.Dial en0-bound", Target: redisAddr, Error: err.Error(), ErrorCategory: classifyError(err)}
 	}
 
 	addrs, err := iface.Addrs()
 	if err != nil {
-		fmt.Printf("  ❌ Failed to get en0 addresses: %v\n", err)
-		return
+		if printText {
+			fmt.Printf("  ❌ Failed to get en0 addresses: %v\n", err)
+		}
+		return testResult{Name: "net.Dial en0-bound", Target: redisAddr, Error: err.Error(), ErrorCategory: classifyError(err)}
 	}
 
 	var localIP net.IP
@@ -263,11 +623,15 @@ func testGoDialWithInterfaceBinding() {
 	}
 
 	if localIP == nil {
-		fmt.Printf("  ❌ No IPv4 address found on en0\n")
-		return
+		if printText {
+			fmt.Printf("  ❌ No IPv4 address found on en0\n")
+		}
+		return testResult{Name: "net.Dial en0-bound", Target: redisAddr, Error: "no IPv4 address found on en0"}
 	}
 
-	fmt.Printf("  Using local IP: %s, interface index: %d\n", localIP, iface.Index)
+	if printText {
+		fmt.Printf("  Using local IP: %s, interface index: %d\n", localIP, iface.Index)
+	}
 
 	dialer := &net.Dialer{
 		Timeout:   5 * time.Second,
@@ -277,10 +641,12 @@ func testGoDialWithInterfaceBinding() {
 			err := c.Control(func(fd uintptr) {
 				// IP_BOUND_IF = 25 on macOS
 				sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, 25, iface.Index)
-				if sockErr != nil {
-					fmt.Printf("  Failed to set IP_BOUND_IF: %v\n", sockErr)
-				} else {
-					fmt.Printf("  Set IP_BOUND_IF to en0 (index %d)\n", iface.Index)
+				if printText {
+					if sockErr != nil {
+						fmt.Printf("  Failed to set IP_BOUND_IF: %v\n", sockErr)
+					} else {
+						fmt.Printf("  Set IP_BOUND_IF to en0 (index %d)\n", iface.Index)
+					}
 				}
 			})
 			if err != nil {
@@ -295,33 +661,299 @@ func testGoDialWithInterfaceBinding() {
 	latency := time.Since(start)
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
-	} else {
-		conn.Close()
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "net.Dial en0-bound", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	conn.Close()
+	if printText {
 		fmt.Printf("  ✅ SUCCEEDED (latency: %v)\n", latency)
 	}
+	return testResult{Name: "net.Dial en0-bound", Target: redisAddr, OK: true, LatencyMS: msSince(start)}
+}
+
+// testRedigoPing exercises the Redis target through gomodule/redigo instead
+// of go-redis. redigo has its own dialer and connection handling, so a
+// success/failure split between this and testGoRedisPing tells us whether
+// the "no route to host" symptom is specific to go-redis's dialer/pool or
+// reproduces under any Go-based Redis client.
+func testRedigoPing(printText bool) testResult {
+	start := time.Now()
+	conn, err := redis.Dial("tcp", redisAddr, redis.DialConnectTimeout(5*time.Second))
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, time.Since(start))
+		}
+		return testResult{Name: "redigo PING", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	defer conn.Close()
+
+	result, err := redis.String(conn.Do("PING"))
+	latency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "redigo PING", Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SUCCEEDED: %s (latency: %v)\n", result, latency)
+	}
+	return testResult{Name: "redigo PING", Target: redisAddr, OK: true, LatencyMS: msSince(start)}
+}
+
+func testRedigoSetGet(printText bool) testResult {
+	cycleStart := time.Now()
+	conn, err := redis.Dial("tcp", redisAddr, redis.DialConnectTimeout(5*time.Second))
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, time.Since(cycleStart))
+		}
+		return testResult{Name: "redigo SET/GET", Target: redisAddr, LatencyMS: msSince(cycleStart), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	defer conn.Close()
+
+	testKey := "go-macos-network-repro-test-redigo"
+	testValue := fmt.Sprintf("test-value-%d", time.Now().Unix())
+
+	// SET
+	start := time.Now()
+	_, err = conn.Do("SET", testKey, testValue, "EX", 60)
+	setLatency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ SET FAILED: %v (latency: %v)\n", err, setLatency)
+		}
+		return testResult{Name: "redigo SET/GET", Target: redisAddr, LatencyMS: msSince(cycleStart), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SET SUCCEEDED (latency: %v)\n", setLatency)
+	}
+
+	// GET
+	start = time.Now()
+	result, err := redis.String(conn.Do("GET", testKey))
+	getLatency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ GET FAILED: %v (latency: %v)\n", err, getLatency)
+		}
+		return testResult{Name: "redigo SET/GET", Target: redisAddr, LatencyMS: msSince(cycleStart), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ GET SUCCEEDED: %s (latency: %v)\n", result, getLatency)
+	}
+	return testResult{Name: "redigo SET/GET", Target: redisAddr, OK: true, LatencyMS: msSince(cycleStart)}
+}
+
+// testSentinelPing pings the Sentinel node directly, confirming Sentinel
+// itself is reachable before trusting the master address it resolves.
+func testSentinelPing(printText bool) testResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	result, err := sentinelClient.Ping(ctx).Result()
+	latency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "sentinel PING", Target: sentinelAddrs[0], LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SUCCEEDED: %s (latency: %v)\n", result, latency)
+	}
+	return testResult{Name: "sentinel PING", Target: sentinelAddrs[0], OK: true, LatencyMS: msSince(start)}
+}
+
+// testSentinelGetMasterAddr asks Sentinel for the current master address for
+// sentinelMaster, so the repro also covers targets learned at runtime rather
+// than the hard-coded redisAddr.
+func testSentinelGetMasterAddr(printText bool) testResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	addr, err := sentinelClient.GetMasterAddrByName(ctx, sentinelMaster).Result()
+	latency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "sentinel get-master-addr-by-name", Target: sentinelMaster, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SUCCEEDED: master at %v (latency: %v)\n", addr, latency)
+	}
+	return testResult{Name: "sentinel get-master-addr-by-name", Target: sentinelMaster, OK: true, LatencyMS: msSince(start)}
+}
+
+// testFailoverPing pings the Sentinel-discovered master through a
+// NewFailoverClient, the client construction HA deployments actually use.
+func testFailoverPing(printText bool) testResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	result, err := failoverClient.Ping(ctx).Result()
+	latency := time.Since(start)
+
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: "failover client PING", Target: sentinelMaster, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if printText {
+		fmt.Printf("  ✅ SUCCEEDED: %s (latency: %v)\n", result, latency)
+	}
+	return testResult{Name: "failover client PING", Target: sentinelMaster, OK: true, LatencyMS: msSince(start)}
 }
 
-func testRouteGet() {
+func testRouteGet(printText bool) (testResult, string) {
+	start := time.Now()
 	cmd := exec.Command("route", "-n", "get", redisHost)
 	out, err := cmd.CombinedOutput()
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v\n", err)
-	} else {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v\n", err)
+		}
+		return testResult{Name: "route get", Target: redisHost, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}, string(out)
+	}
+	if printText {
 		fmt.Printf("  Output:\n%s\n", string(out))
 	}
+	return testResult{Name: "route get", Target: redisHost, OK: true, LatencyMS: msSince(start)}, string(out)
 }
 
-func testArp() {
+func testArp(printText bool) (testResult, string) {
+	start := time.Now()
 	cmd := exec.Command("arp", "-n", redisHost)
 	out, err := cmd.CombinedOutput()
 
 	if err != nil {
-		fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
-	} else {
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v\n  Output: %s\n", err, string(out))
+		}
+		return testResult{Name: "arp", Target: redisHost, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}, string(out)
+	}
+	if printText {
 		fmt.Printf("  Output: %s\n", string(out))
 	}
+	return testResult{Name: "arp", Target: redisHost, OK: true, LatencyMS: msSince(start)}, string(out)
+}
+
+// testRawSyscallDial opens a raw AF_INET/SOCK_STREAM socket and connects to
+// the Redis host using syscall.Connect directly, bypassing net.Dial and the
+// Go runtime netpoller entirely. This is the missing data point between
+// "system nc works" and "Go net.Dial fails": it tells us whether the
+// regression is at the Go netpoller layer, the net package's routing
+// decision, or the kernel socket layer for this process. The raw errno
+// (e.g. EHOSTUNREACH, ENETUNREACH, ETIMEDOUT) is logged so the failure can
+// be correlated against Darwin kernel source.
+func testRawSyscallDial(printText bool) testResult {
+	const name = "raw syscall connect"
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ socket() FAILED: %v\n", err)
+		}
+		return testResult{Name: name, Target: redisAddr, Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	defer syscall.Close(fd)
+
+	if iface, ifaceErr := net.InterfaceByName("en0"); ifaceErr == nil {
+		// IP_BOUND_IF = 25 on macOS
+		if sockErr := syscall.SetsockoptInt(fd, syscall.IPPROTO_IP, 25, iface.Index); sockErr != nil {
+			if printText {
+				fmt.Printf("  Failed to set IP_BOUND_IF: %v\n", sockErr)
+			}
+		} else if printText {
+			fmt.Printf("  Set IP_BOUND_IF to en0 (index %d)\n", iface.Index)
+		}
+	}
+
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		if printText {
+			fmt.Printf("  ❌ SetNonblock FAILED: %v\n", err)
+		}
+		return testResult{Name: name, Target: redisAddr, Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+
+	sa := &syscall.SockaddrInet4{Port: redisPort}
+	copy(sa.Addr[:], net.ParseIP(redisHost).To4())
+
+	start := time.Now()
+	if err := syscall.Connect(fd, sa); err != nil && err != syscall.EINPROGRESS {
+		latency := time.Since(start)
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (errno %d) (latency: %v)\n", err, err, latency)
+		}
+		return testResult{Name: name, Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+
+	// Wait for the non-blocking connect to complete. Unlike Linux,
+	// syscall.Select on darwin returns a single error value (no ready-count),
+	// so completion vs. timeout is told apart by checking whether fd is still
+	// set in the fd_set select() populates in place.
+	var writeFDs syscall.FdSet
+	fdSet(&writeFDs, fd)
+	timeout := syscall.Timeval{Sec: 5}
+	err = syscall.Select(fd+1, nil, &writeFDs, nil, &timeout)
+	latency := time.Since(start)
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ select() FAILED: %v (latency: %v)\n", err, latency)
+		}
+		return testResult{Name: name, Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if !fdIsSet(&writeFDs, fd) {
+		if printText {
+			fmt.Printf("  ❌ FAILED: connect timed out (latency: %v)\n", latency)
+		}
+		return testResult{Name: name, Target: redisAddr, LatencyMS: msSince(start), Error: "connect timed out", ErrorCategory: "i/o timeout"}
+	}
+
+	// SO_ERROR holds the real connect() outcome for a non-blocking socket.
+	soErr, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_ERROR)
+	if err != nil {
+		if printText {
+			fmt.Printf("  ❌ getsockopt(SO_ERROR) FAILED: %v\n", err)
+		}
+		return testResult{Name: name, Target: redisAddr, LatencyMS: msSince(start), Error: err.Error(), ErrorCategory: classifyError(err)}
+	}
+	if soErr != 0 {
+		errno := syscall.Errno(soErr)
+		if printText {
+			fmt.Printf("  ❌ FAILED: %v (errno %d) (latency: %v)\n", errno, soErr, latency)
+		}
+		return testResult{Name: name, Target: redisAddr, LatencyMS: msSince(start), Error: errno.Error(), ErrorCategory: classifyError(errno)}
+	}
+
+	if printText {
+		fmt.Printf("  ✅ SUCCEEDED (latency: %v)\n", latency)
+	}
+	return testResult{Name: name, Target: redisAddr, OK: true, LatencyMS: msSince(start)}
+}
+
+// fdSet sets fd's bit in an FdSet for use with syscall.Select.
+func fdSet(set *syscall.FdSet, fd int) {
+	set.Bits[fd/32] |= 1 << (uint(fd) % 32)
+}
+
+// fdIsSet reports whether fd's bit is set in an FdSet that syscall.Select
+// has populated in place.
+func fdIsSet(set *syscall.FdSet, fd int) bool {
+	return set.Bits[fd/32]&(1<<(uint(fd)%32)) != 0
 }
 
 // HTTPTest tests if HTTP requests work (uses Go's http package)